@@ -12,6 +12,8 @@ const (
 	LOC_CENTER_X = 108
 	LOC_CENTER_Y = 111
 	LOC_RADIUS   = 40
+	// Screens with all sampled channels within this range are treated as blank frames
+	BLANK_SCREEN_TOLERANCE = 8
 )
 
 // Rotation inference configuration
@@ -517,6 +517,12 @@ func (i *MapTrackerInfer) loadPointer(ctx *maa.Context) (*image.RGBA, error) {
 func (i *MapTrackerInfer) inferLocation(screenImg *image.RGBA, mapNameRegex *regexp.Regexp, param *MapTrackerInferParam) *InferLocationRawResult {
 	t0 := time.Now()
 
+	// Skip loading screens and other blank frames
+	if isBlankScreen(screenImg) {
+		log.Debug().Msg("Screen is blank, skipping location inference")
+		return nil
+	}
+
 	// Use cached scaled maps
 	scale := param.Precision
 	scaledMaps := i.getScaledMaps(scale)
@@ -690,6 +696,13 @@ func (i *MapTrackerInfer) inferLocation(screenImg *image.RGBA, mapNameRegex *reg
 	}
 }
 
+// isBlankScreen reports whether the whole screen is a solid-color frame (e.g. a loading screen).
+// The check is done on the full screen rather than the mini-map crop,
+// since low-contrast mini-map areas are still valid matching input
+func isBlankScreen(screenImg *image.RGBA) bool {
+	return minicv.IsUniformFrame(screenImg, BLANK_SCREEN_TOLERANCE)
+}
+
 // getScaledMaps returns cached scaled maps or recomputes them
 func (i *MapTrackerInfer) getScaledMaps(scale float64) []MapCache {
 	i.scaledMu.Lock()
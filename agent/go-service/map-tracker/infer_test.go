@@ -0,0 +1,74 @@
+package maptracker
+
+import (
+	"image"
+	"image/draw"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/MaaXYZ/MaaEnd/agent/go-service/pkg/minicv"
+)
+
+func TestIsBlankScreenSolid(t *testing.T) {
+	screen := image.NewRGBA(image.Rect(0, 0, WORK_W, WORK_H))
+	for i := range screen.Pix {
+		screen.Pix[i] = 5
+	}
+	if !isBlankScreen(screen) {
+		t.Error("solid screen should be blank")
+	}
+}
+
+// TestIsBlankScreenLowContrastMiniMap checks that a real low-contrast mini-map crop,
+// which a crop-level uniformity check would reject, does not make a game screen blank
+func TestIsBlankScreenLowContrastMiniMap(t *testing.T) {
+	mapPath := filepath.Join("..", "..", "..", "assets", "resource", MAP_DIR, "map01_lv003_tier_19.png")
+	file, err := os.Open(mapPath)
+	if err != nil {
+		t.Skipf("map asset not available: %v", err)
+	}
+	img, _, err := image.Decode(file)
+	file.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapImg := minicv.ImageConvertRGBA(img)
+
+	// Find an opaque, non-flat mini-map sized window that is within tolerance on the sample grid
+	size := LOC_RADIUS*2 + 1
+	var lowContrast *image.RGBA
+	for y := 0; y+size <= mapImg.Rect.Dy() && lowContrast == nil; y += 10 {
+		for x := 0; x+size <= mapImg.Rect.Dx(); x += 10 {
+			crop := image.NewRGBA(image.Rect(0, 0, size, size))
+			draw.Draw(crop, crop.Rect, mapImg, image.Pt(x, y), draw.Src)
+			if minicv.GetImageStats(crop).Std > 1e-6 && isOpaque(crop) &&
+				minicv.IsUniformFrame(crop, BLANK_SCREEN_TOLERANCE) {
+				lowContrast = crop
+				break
+			}
+		}
+	}
+	if lowContrast == nil {
+		t.Skip("no low-contrast window found in map asset")
+	}
+
+	// Build a game-like screen from the map and put the low-contrast crop in the mini-map slot
+	screen := image.NewRGBA(image.Rect(0, 0, WORK_W, WORK_H))
+	draw.Draw(screen, screen.Rect, mapImg, image.Point{}, draw.Src)
+	miniMapRect := image.Rect(LOC_CENTER_X-LOC_RADIUS, LOC_CENTER_Y-LOC_RADIUS, LOC_CENTER_X+LOC_RADIUS+1, LOC_CENTER_Y+LOC_RADIUS+1)
+	draw.Draw(screen, miniMapRect, lowContrast, image.Point{}, draw.Src)
+
+	if isBlankScreen(screen) {
+		t.Error("screen with a low-contrast mini-map should not be blank")
+	}
+}
+
+func isOpaque(img *image.RGBA) bool {
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] != 255 {
+			return false
+		}
+	}
+	return true
+}
@@ -2,6 +2,7 @@
 package minicv
 
 import (
+	"fmt"
 	"image"
 	"image/draw"
 	"math"
@@ -9,6 +10,26 @@ import (
 	xdraw "golang.org/x/image/draw"
 )
 
+// lumaWeights are the integer RGB weights used by Luma; the weighted sum is divided by their total
+var lumaWeights = [3]int{3, 6, 1}
+
+// SetLumaWeights overrides the integer RGB weights used by Luma (default 3, 6, 1).
+// Weights must be non-negative with a positive sum.
+// It is not safe to call concurrently with image processing, so set it once during initialization
+func SetLumaWeights(r, g, b int) error {
+	if r < 0 || g < 0 || b < 0 || r+g+b <= 0 {
+		return fmt.Errorf("invalid luma weights: %d, %d, %d", r, g, b)
+	}
+	lumaWeights = [3]int{r, g, b}
+	return nil
+}
+
+// Luma returns the luma of an RGB color weighted by the configured luma weights
+func Luma(r, g, b int) int {
+	w := lumaWeights
+	return (r*w[0] + g*w[1] + b*w[2]) / (w[0] + w[1] + w[2])
+}
+
 // ImageCropSquareByRadius crops a square region from the image centered at (centerX, centerY) with the given radius
 func ImageCropSquareByRadius(img *image.RGBA, centerX, centerY, radius int) *image.RGBA {
 	x1, x2 := max(img.Rect.Min.X, centerX-radius), min(img.Rect.Max.X, centerX+radius+1)
@@ -65,13 +86,285 @@ func ImageScale(img *image.RGBA, scale float64) *image.RGBA {
 	return dst
 }
 
+// InterpMode selects the interpolation used by Downscale
+type InterpMode int
+
+const (
+	InterpNearest  InterpMode = iota // Nearest neighbor, fastest but aliased
+	InterpBilinear                   // Bilinear interpolation
+	InterpBox                        // Box filter averaging the source block behind each output pixel, smoothest
+)
+
+// Downscale shrinks an image by an integer factor using the given interpolation mode.
+// The result is always a new image with its origin at 0,0, even when scale is 1.
+// An empty input yields an empty image; a scale below 1 or an unknown mode yields nil
+func Downscale(img image.Image, scale int, mode InterpMode) *image.RGBA {
+	if scale < 1 || (mode != InterpNearest && mode != InterpBilinear && mode != InterpBox) {
+		return nil
+	}
+	src := ImageConvertRGBA(img)
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	if w == 0 || h == 0 {
+		return image.NewRGBA(image.Rect(0, 0, 0, 0))
+	}
+	newW, newH := max(1, w/scale), max(1, h/scale)
+	dst := image.NewRGBA(image.Rect(0, 0, newW, newH))
+
+	switch mode {
+	case InterpNearest:
+		xdraw.NearestNeighbor.Scale(dst, dst.Rect, src, src.Rect, xdraw.Src, nil)
+	case InterpBilinear:
+		xdraw.BiLinear.Scale(dst, dst.Rect, src, src.Rect, xdraw.Src, nil)
+	case InterpBox:
+		downscaleBox(dst, src)
+	}
+	return dst
+}
+
+// downscaleBox fills dst with the average of the src block behind each pixel.
+// Block bounds are spread over the full source so trailing rows and columns are not dropped
+func downscaleBox(dst, src *image.RGBA) {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dw, dh := dst.Rect.Dx(), dst.Rect.Dy()
+	spx, ss := src.Pix, src.Stride
+	dpx, ds := dst.Pix, dst.Stride
+
+	for dy := range dh {
+		y1, y2 := dy*h/dh, (dy+1)*h/dh
+		for dx := range dw {
+			x1, x2 := dx*w/dw, (dx+1)*w/dw
+			var sum [4]int
+			for y := y1; y < y2; y++ {
+				off := y*ss + x1*4
+				for x := x1; x < x2; x++ {
+					sum[0] += int(spx[off])
+					sum[1] += int(spx[off+1])
+					sum[2] += int(spx[off+2])
+					sum[3] += int(spx[off+3])
+					off += 4
+				}
+			}
+			count := (x2 - x1) * (y2 - y1)
+			off := dy*ds + dx*4
+			for c := range 4 {
+				dpx[off+c] = uint8(sum[c] / count)
+			}
+		}
+	}
+}
+
 // ImageConvertRGBA converts any image.Image to *image.RGBA
 func ImageConvertRGBA(img image.Image) *image.RGBA {
-	if dst, ok := img.(*image.RGBA); ok {
-		return dst
+	switch src := img.(type) {
+	case *image.RGBA:
+		return src
+	case *image.RGBA64:
+		return convertRGBA64(src)
+	case *image.NRGBA64:
+		return convertNRGBA64(src)
 	}
+	// draw.Draw already has a fast path for *image.NRGBA and other common types
 	b := img.Bounds()
 	dst := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
 	draw.Draw(dst, dst.Bounds(), img, b.Min, draw.Src)
 	return dst
 }
+
+// convertRGBA64 converts a 16-bit premultiplied image by keeping the high byte of each channel
+func convertRGBA64(img *image.RGBA64) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	dpx, ds := dst.Pix, dst.Stride
+	ipx, is := img.Pix, img.Stride
+
+	for y := range h {
+		dOff, iOff := y*ds, y*is
+		for range w {
+			dpx[dOff] = ipx[iOff]
+			dpx[dOff+1] = ipx[iOff+2]
+			dpx[dOff+2] = ipx[iOff+4]
+			dpx[dOff+3] = ipx[iOff+6]
+			dOff += 4
+			iOff += 8
+		}
+	}
+	return dst
+}
+
+// convertNRGBA64 converts a 16-bit non-premultiplied image, premultiplying by alpha
+func convertNRGBA64(img *image.NRGBA64) *image.RGBA {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	dpx, ds := dst.Pix, dst.Stride
+	ipx, is := img.Pix, img.Stride
+
+	for y := range h {
+		dRow, iRow := dpx[y*ds:y*ds+w*4], ipx[y*is:y*is+w*8]
+		for x := range w {
+			d, p := dRow[x*4:x*4+4:x*4+4], iRow[x*8:x*8+8:x*8+8]
+			a := uint32(p[6])<<8 | uint32(p[7])
+			d[0] = uint8((uint32(p[0])<<8 | uint32(p[1])) * a / 0xffff >> 8)
+			d[1] = uint8((uint32(p[2])<<8 | uint32(p[3])) * a / 0xffff >> 8)
+			d[2] = uint8((uint32(p[4])<<8 | uint32(p[5])) * a / 0xffff >> 8)
+			d[3] = uint8(a >> 8)
+		}
+	}
+	return dst
+}
+
+// IsUniformFrame samples the image on a coarse grid and reports whether
+// all sampled pixels are within tolerance of each other on every RGB channel
+func IsUniformFrame(img *image.RGBA, tolerance int) bool {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w == 0 || h == 0 {
+		return true
+	}
+	ipx, is := img.Pix, img.Stride
+
+	const gridSize = 16
+	stepX, stepY := max(1, w/gridSize), max(1, h/gridSize)
+
+	minC := [3]int{255, 255, 255}
+	maxC := [3]int{0, 0, 0}
+	for y := stepY / 2; y < h; y += stepY {
+		for x := stepX / 2; x < w; x += stepX {
+			off := y*is + x*4
+			for c := range 3 {
+				v := int(ipx[off+c])
+				minC[c], maxC[c] = min(minC[c], v), max(maxC[c], v)
+			}
+		}
+	}
+	for c := range 3 {
+		if maxC[c]-minC[c] > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// AdaptiveBinarize binarizes an image using local mean thresholding.
+// A pixel becomes white if its luma is above the mean of the surrounding
+// blockSize x blockSize window minus c, otherwise black
+func AdaptiveBinarize(src image.Image, blockSize int, c int) *image.RGBA {
+	img := ImageConvertRGBA(src)
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	ipx, is := img.Pix, img.Stride
+
+	// Luma plane and its integral array
+	luma := make([]int, w*h)
+	sum := make([]int, (w+1)*(h+1))
+	stride := w + 1
+	for y := range h {
+		rowSum := 0
+		off := y * is
+		for x := range w {
+			l := Luma(int(ipx[off]), int(ipx[off+1]), int(ipx[off+2]))
+			luma[y*w+x] = l
+			rowSum += l
+			sum[(y+1)*stride+(x+1)] = sum[y*stride+(x+1)] + rowSum
+			off += 4
+		}
+	}
+
+	half := max(1, blockSize) / 2
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	dpx, ds := dst.Pix, dst.Stride
+	for y := range h {
+		y1, y2 := max(0, y-half), min(h, y+half+1)
+		off := y * ds
+		for x := range w {
+			x1, x2 := max(0, x-half), min(w, x+half+1)
+			area := (x2 - x1) * (y2 - y1)
+			s := sum[y2*stride+x2] - sum[y1*stride+x2] - sum[y2*stride+x1] + sum[y1*stride+x1]
+			var v uint8
+			if luma[y*w+x]*area > s-c*area {
+				v = 255
+			}
+			dpx[off], dpx[off+1], dpx[off+2], dpx[off+3] = v, v, v, 255
+			off += 4
+		}
+	}
+	return dst
+}
+
+// GradientMagnitudeImage computes the luma gradient magnitude |gradX|+|gradY| of each pixel,
+// using forward differences and clamping the result to 255
+func GradientMagnitudeImage(img *image.RGBA) *image.Gray {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	ipx, is := img.Pix, img.Stride
+
+	luma := make([]int, w*h)
+	for y := range h {
+		off := y * is
+		for x := range w {
+			luma[y*w+x] = Luma(int(ipx[off]), int(ipx[off+1]), int(ipx[off+2]))
+			off += 4
+		}
+	}
+
+	dst := image.NewGray(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			l := luma[y*w+x]
+			gx, gy := 0, 0
+			if x+1 < w {
+				gx = luma[y*w+x+1] - l
+			}
+			if y+1 < h {
+				gy = luma[(y+1)*w+x] - l
+			}
+			dst.Pix[y*dst.Stride+x] = uint8(min(255, absInt(gx)+absInt(gy)))
+		}
+	}
+	return dst
+}
+
+// DetectCircleRadius locates a disk (e.g. a circular mini-map) drawn on a uniform border.
+// It scans the middle row and column inward from the edges for the first pixel that differs
+// from the border color (sampled at the top-left corner), then rescans through the estimated
+// center to refine it. ok is false if no disk is found or its horizontal and vertical extents disagree
+func DetectCircleRadius(img *image.RGBA) (cx, cy int, radius float64, ok bool) {
+	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w < 3 || h < 3 {
+		return 0, 0, 0, false
+	}
+	ipx, is := img.Pix, img.Stride
+
+	const borderTolerance = 16
+	border := ipx[0:3]
+	isBorder := func(x, y int) bool {
+		off := y*is + x*4
+		return absInt(int(ipx[off])-int(border[0])) <= borderTolerance &&
+			absInt(int(ipx[off+1])-int(border[1])) <= borderTolerance &&
+			absInt(int(ipx[off+2])-int(border[2])) <= borderTolerance
+	}
+
+	cx, cy = w/2, h/2
+	var rx, ry float64
+	for range 2 {
+		left, right := 0, w-1
+		for left < w && isBorder(left, cy) {
+			left++
+		}
+		for right >= 0 && isBorder(right, cy) {
+			right--
+		}
+		top, bottom := 0, h-1
+		for top < h && isBorder(cx, top) {
+			top++
+		}
+		for bottom >= 0 && isBorder(cx, bottom) {
+			bottom--
+		}
+		if left > right || top > bottom {
+			return 0, 0, 0, false
+		}
+		cx, cy = (left+right)/2, (top+bottom)/2
+		rx, ry = float64(right-left+1)/2, float64(bottom-top+1)/2
+	}
+
+	radius = (rx + ry) / 2
+	ok = math.Abs(rx-ry) <= max(1.0, radius*0.1)
+	return cx, cy, radius, ok
+}
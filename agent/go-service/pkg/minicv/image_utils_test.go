@@ -0,0 +1,393 @@
+package minicv
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"testing"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// drawDisk fills a w x h image with a uniform border color and draws a textured disk on it
+func drawDisk(w, h, cx, cy, r int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			c := color.RGBA{20, 20, 20, 255}
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= r*r {
+				c = color.RGBA{uint8(100 + x), uint8(50 + y), 90, 255}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestDetectCircleRadius(t *testing.T) {
+	img := drawDisk(120, 100, 55, 48, 35)
+	cx, cy, radius, ok := DetectCircleRadius(img)
+	if !ok {
+		t.Fatal("expected disk to be detected")
+	}
+	if absInt(cx-55) > 1 || absInt(cy-48) > 1 {
+		t.Errorf("center = (%d, %d), want about (55, 48)", cx, cy)
+	}
+	if math.Abs(radius-35) > 1.5 {
+		t.Errorf("radius = %.2f, want about 35", radius)
+	}
+}
+
+func TestDetectCircleRadiusBlank(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 64))
+	if _, _, _, ok := DetectCircleRadius(img); ok {
+		t.Error("expected no disk on a blank image")
+	}
+}
+
+func TestIsUniformFrame(t *testing.T) {
+	solid := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for i := range solid.Pix {
+		solid.Pix[i] = 30
+	}
+	if !IsUniformFrame(solid, 0) {
+		t.Error("solid image should be uniform")
+	}
+
+	// Small noise within tolerance still counts as uniform
+	noisy := image.NewRGBA(solid.Rect)
+	for i := range noisy.Pix {
+		noisy.Pix[i] = uint8(30 + i%5)
+	}
+	if !IsUniformFrame(noisy, 4) {
+		t.Error("noise within tolerance should be uniform")
+	}
+
+	textured := drawDisk(64, 48, 32, 24, 20)
+	if IsUniformFrame(textured, 8) {
+		t.Error("textured image should not be uniform")
+	}
+
+	if !IsUniformFrame(image.NewRGBA(image.Rect(0, 0, 0, 0)), 0) {
+		t.Error("empty image should be uniform")
+	}
+}
+
+func TestAdaptiveBinarizeGradientLighting(t *testing.T) {
+	// Lighting ramps from dark on the left to bright on the right,
+	// and the icon is a bar slightly brighter than its local background
+	const w, h = 60, 20
+	icon := image.Rect(5, 6, 55, 14)
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	lumaSum := 0
+	for y := range h {
+		for x := range w {
+			v := 20 + 3*x
+			if image.Pt(x, y).In(icon) {
+				v += 40
+			}
+			lumaSum += v
+			img.SetRGBA(x, y, color.RGBA{uint8(v), uint8(v), uint8(v), 255})
+		}
+	}
+
+	// A global mean threshold loses the dark end of the icon
+	globalMean := lumaSum / (w * h)
+	if 20+3*icon.Min.X+40 > globalMean {
+		t.Fatalf("fixture should defeat global thresholding (mean %d)", globalMean)
+	}
+
+	bin := AdaptiveBinarize(img, 21, 5)
+	for y := icon.Min.Y; y < icon.Max.Y; y++ {
+		for x := icon.Min.X; x < icon.Max.X; x++ {
+			if bin.RGBAAt(x, y).R != 255 {
+				t.Fatalf("icon pixel (%d, %d) should be white", x, y)
+			}
+		}
+	}
+	for _, y := range []int{0, 1, h - 2, h - 1} {
+		for x := 10; x < 50; x++ {
+			if bin.RGBAAt(x, y).R != 0 {
+				t.Fatalf("background pixel (%d, %d) should be black", x, y)
+			}
+		}
+	}
+}
+
+func TestImageConvertRGBAMatchesDraw(t *testing.T) {
+	rect := image.Rect(0, 0, 7, 5)
+	rgba64 := image.NewRGBA64(rect)
+	nrgba64 := image.NewNRGBA64(rect)
+	nrgba := image.NewNRGBA(rect)
+	for y := range 5 {
+		for x := range 7 {
+			a := uint16(0x2000 + 0x1800*y + 0x300*x)
+			v := uint16(0x1357*x + 0x2468*y)
+			// RGBA64 is premultiplied, so channels must not exceed alpha
+			rgba64.SetRGBA64(x, y, color.RGBA64{v % (a + 1), a / 2, a, a})
+			nrgba64.SetNRGBA64(x, y, color.NRGBA64{v, 0xffff - v, 0x8000, a})
+			nrgba.SetNRGBA(x, y, color.NRGBA{uint8(v >> 8), uint8(x * 30), uint8(y * 50), uint8(a >> 8)})
+		}
+	}
+	sub := image.Rect(2, 1, 6, 4)
+
+	cases := []struct {
+		name string
+		img  image.Image
+	}{
+		{"RGBA64", rgba64},
+		{"NRGBA64", nrgba64},
+		{"NRGBA", nrgba},
+		{"RGBA64 sub-image", rgba64.SubImage(sub)},
+		{"NRGBA64 sub-image", nrgba64.SubImage(sub)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := c.img.Bounds()
+			want := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+			draw.Draw(want, want.Rect, c.img, b.Min, draw.Src)
+
+			got := ImageConvertRGBA(c.img)
+			if got.Rect != want.Rect {
+				t.Fatalf("rect = %v, want %v", got.Rect, want.Rect)
+			}
+			for y := range b.Dy() {
+				for x := range b.Dx() {
+					if g, w := got.RGBAAt(x, y), want.RGBAAt(x, y); g != w {
+						t.Fatalf("pixel (%d, %d) = %v, want %v", x, y, g, w)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkImageConvertRGBA(b *testing.B) {
+	// Screenshot-sized inputs, compared against the generic per-pixel draw.Draw path
+	rect := image.Rect(0, 0, 1280, 720)
+	rgba64 := image.NewRGBA64(rect)
+	nrgba64 := image.NewNRGBA64(rect)
+	for i := range rgba64.Pix {
+		rgba64.Pix[i] = uint8(i * 7)
+		nrgba64.Pix[i] = uint8(i * 13)
+	}
+
+	for _, c := range []struct {
+		name string
+		img  image.Image
+	}{
+		{"RGBA64", rgba64},
+		{"NRGBA64", nrgba64},
+	} {
+		b.Run(c.name+"/fast", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				ImageConvertRGBA(c.img)
+			}
+		})
+		b.Run(c.name+"/draw", func(b *testing.B) {
+			b.ReportAllocs()
+			for b.Loop() {
+				dst := image.NewRGBA(rect)
+				draw.Draw(dst, dst.Rect, c.img, rect.Min, draw.Src)
+			}
+		})
+	}
+}
+
+func TestGradientMagnitudeImage(t *testing.T) {
+	// Vertical edge between a dark left half and a bright right half
+	img := image.NewRGBA(image.Rect(0, 0, 10, 6))
+	for y := range 6 {
+		for x := range 10 {
+			v := uint8(20)
+			if x >= 5 {
+				v = 220
+			}
+			img.SetRGBA(x, y, color.RGBA{v, v, v, 255})
+		}
+	}
+
+	grad := GradientMagnitudeImage(img)
+	if grad.Rect != image.Rect(0, 0, 10, 6) {
+		t.Fatalf("rect = %v", grad.Rect)
+	}
+	for y := range 6 {
+		for x := range 10 {
+			want := uint8(0)
+			if x == 4 {
+				want = 200
+			}
+			if got := grad.GrayAt(x, y).Y; got != want {
+				t.Errorf("gradient at (%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestLumaDefaults(t *testing.T) {
+	cases := []struct {
+		r, g, b, want int
+	}{
+		{0, 0, 0, 0},
+		{255, 255, 255, 255},
+		{255, 0, 0, 76},
+		{0, 255, 0, 153},
+		{0, 0, 255, 25},
+		{100, 100, 100, 100},
+		{200, 120, 40, 136},
+	}
+	for _, c := range cases {
+		if got := Luma(c.r, c.g, c.b); got != c.want {
+			t.Errorf("Luma(%d, %d, %d) = %d, want %d", c.r, c.g, c.b, got, c.want)
+		}
+	}
+}
+
+func TestSetLumaWeights(t *testing.T) {
+	t.Cleanup(func() { lumaWeights = [3]int{3, 6, 1} })
+
+	if err := SetLumaWeights(0, 0, 0); err == nil {
+		t.Error("zero-sum weights should be rejected")
+	}
+	if err := SetLumaWeights(-1, 5, 5); err == nil {
+		t.Error("negative weights should be rejected")
+	}
+	if got := Luma(255, 0, 0); got != 76 {
+		t.Errorf("rejected weights must not apply, Luma = %d", got)
+	}
+
+	if err := SetLumaWeights(1, 1, 1); err != nil {
+		t.Fatal(err)
+	}
+	if got := Luma(30, 60, 90); got != 60 {
+		t.Errorf("Luma with equal weights = %d, want 60", got)
+	}
+}
+
+// boxAverage averages src over the block spread evenly behind each pixel of a dw x dh image
+func boxAverage(src *image.RGBA, dw, dh int) *image.RGBA {
+	w, h := src.Rect.Dx(), src.Rect.Dy()
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+	for by := range dh {
+		for bx := range dw {
+			x1, x2 := bx*w/dw, (bx+1)*w/dw
+			y1, y2 := by*h/dh, (by+1)*h/dh
+			for c := range 4 {
+				sum := 0
+				for y := y1; y < y2; y++ {
+					for x := x1; x < x2; x++ {
+						sum += int(src.Pix[y*src.Stride+x*4+c])
+					}
+				}
+				dst.Pix[by*dst.Stride+bx*4+c] = uint8(sum / ((x2 - x1) * (y2 - y1)))
+			}
+		}
+	}
+	return dst
+}
+
+func TestDownscaleModes(t *testing.T) {
+	// 9x6 divides evenly by 3, 10x7 leaves a trailing column and row
+	for _, size := range []image.Point{{9, 6}, {10, 7}} {
+		src := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+		for i := range src.Pix {
+			if i%4 == 3 {
+				src.Pix[i] = 255
+			} else {
+				src.Pix[i] = uint8(i * 37)
+			}
+		}
+		dstRect := image.Rect(0, 0, size.X/3, size.Y/3)
+
+		nearest := image.NewRGBA(dstRect)
+		xdraw.NearestNeighbor.Scale(nearest, dstRect, src, src.Rect, xdraw.Src, nil)
+		bilinear := image.NewRGBA(dstRect)
+		xdraw.BiLinear.Scale(bilinear, dstRect, src, src.Rect, xdraw.Src, nil)
+		box := boxAverage(src, dstRect.Dx(), dstRect.Dy())
+
+		// The modes must actually differ on this input
+		if string(nearest.Pix) == string(box.Pix) || string(bilinear.Pix) == string(box.Pix) {
+			t.Errorf("%v: fixture should distinguish the modes", size)
+		}
+
+		cases := []struct {
+			name string
+			mode InterpMode
+			want *image.RGBA
+		}{
+			{"nearest", InterpNearest, nearest},
+			{"bilinear", InterpBilinear, bilinear},
+			{"box", InterpBox, box},
+		}
+		for _, c := range cases {
+			t.Run(fmt.Sprintf("%s %dx%d", c.name, size.X, size.Y), func(t *testing.T) {
+				got := Downscale(src, 3, c.mode)
+				if got.Rect != dstRect {
+					t.Fatalf("rect = %v, want %v", got.Rect, dstRect)
+				}
+				for i := range got.Pix {
+					if got.Pix[i] != c.want.Pix[i] {
+						t.Fatalf("byte %d = %d, want %d", i, got.Pix[i], c.want.Pix[i])
+					}
+				}
+				if empty := Downscale(image.NewRGBA(image.Rect(0, 0, 0, 0)), 2, c.mode); empty == nil || !empty.Rect.Empty() {
+					t.Errorf("zero-size input should yield an empty image, got %v", empty)
+				}
+			})
+		}
+	}
+
+	if Downscale(image.NewRGBA(image.Rect(0, 0, 9, 6)), 2, InterpMode(99)) != nil {
+		t.Error("unknown mode should return nil")
+	}
+}
+
+func TestDownscaleBoxCoversTrailingPixels(t *testing.T) {
+	// A 10x1 ramp shrunk by 3: the last block must include the tenth column
+	src := image.NewRGBA(image.Rect(0, 0, 10, 1))
+	for x := range 10 {
+		v := uint8(25 * x)
+		src.SetRGBA(x, 0, color.RGBA{v, v, v, 255})
+	}
+	got := Downscale(src, 3, InterpBox)
+	want := []uint8{25, 100, 187}
+	for x, v := range want {
+		if g := got.RGBAAt(x, 0).R; g != v {
+			t.Errorf("box pixel %d = %d, want %d", x, g, v)
+		}
+	}
+}
+
+func TestDownscaleScaleOne(t *testing.T) {
+	base := image.NewRGBA(image.Rect(0, 0, 10, 3))
+	for i := range base.Pix {
+		base.Pix[i] = uint8(i * 11)
+	}
+	src := base.SubImage(image.Rect(2, 0, 8, 1)).(*image.RGBA)
+
+	for _, mode := range []InterpMode{InterpNearest, InterpBilinear, InterpBox} {
+		got := Downscale(src, 1, mode)
+		if got.Rect != image.Rect(0, 0, 6, 1) {
+			t.Fatalf("mode %d: rect = %v, want origin at 0,0", mode, got.Rect)
+		}
+		for x := range 6 {
+			if g, w := got.RGBAAt(x, 0), src.RGBAAt(x+2, 0); g != w {
+				t.Fatalf("mode %d: pixel %d = %v, want %v", mode, x, g, w)
+			}
+		}
+		// The result must not alias the caller's pixels
+		got.Pix[0]++
+		if got.Pix[0] == src.Pix[0] {
+			t.Fatalf("mode %d: result aliases the source", mode)
+		}
+	}
+
+	for _, scale := range []int{0, -2} {
+		if Downscale(src, scale, InterpBox) != nil {
+			t.Errorf("scale %d should return nil", scale)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright (c) 2026 Harry Huang
+package minicv
+
+import (
+	"fmt"
+	"image"
+)
+
+// BuildStaticMaskFromReference builds a mask by diffing a frame against a reference frame without dynamic content.
+// A pixel is marked valid (255) only where any RGB channel differs by more than threshold,
+// i.e. where real content appears on top of the static decorations
+func BuildStaticMaskFromReference(withContent, empty *image.RGBA, threshold int) (*image.Alpha, error) {
+	w, h := withContent.Rect.Dx(), withContent.Rect.Dy()
+	if w != empty.Rect.Dx() || h != empty.Rect.Dy() {
+		return nil, fmt.Errorf("reference size mismatch: %dx%d vs %dx%d", w, h, empty.Rect.Dx(), empty.Rect.Dy())
+	}
+
+	mask := image.NewAlpha(image.Rect(0, 0, w, h))
+	cpx, cs := withContent.Pix, withContent.Stride
+	epx, es := empty.Pix, empty.Stride
+
+	for y := range h {
+		cOff, eOff := y*cs, y*es
+		mOff := y * mask.Stride
+		for x := range w {
+			d := max(
+				absInt(int(cpx[cOff])-int(epx[eOff])),
+				absInt(int(cpx[cOff+1])-int(epx[eOff+1])),
+				absInt(int(cpx[cOff+2])-int(epx[eOff+2])),
+			)
+			if d > threshold {
+				mask.Pix[mOff+x] = 255
+			}
+			cOff += 4
+			eOff += 4
+		}
+	}
+	return mask, nil
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// MaskOp is a composition operation for CombineMasks
+type MaskOp int
+
+const (
+	MaskAnd      MaskOp = iota // Intersection (per-pixel minimum)
+	MaskOr                     // Union (per-pixel maximum)
+	MaskSubtract               // First mask minus all following masks
+)
+
+// CombineMasks composes alpha masks of identical dimensions with the given operation.
+// Returns nil for an unknown operation, if no masks are given, any mask is nil or their dimensions differ
+func CombineMasks(mode MaskOp, masks ...*image.Alpha) *image.Alpha {
+	if mode != MaskAnd && mode != MaskOr && mode != MaskSubtract {
+		return nil
+	}
+	if len(masks) == 0 || masks[0] == nil {
+		return nil
+	}
+	w, h := masks[0].Rect.Dx(), masks[0].Rect.Dy()
+	for _, m := range masks[1:] {
+		if m == nil || m.Rect.Dx() != w || m.Rect.Dy() != h {
+			return nil
+		}
+	}
+
+	dst := image.NewAlpha(image.Rect(0, 0, w, h))
+	for y := range h {
+		dOff := y * dst.Stride
+		copy(dst.Pix[dOff:dOff+w], masks[0].Pix[y*masks[0].Stride:])
+		for _, m := range masks[1:] {
+			mOff := y * m.Stride
+			for x := range w {
+				a, b := dst.Pix[dOff+x], m.Pix[mOff+x]
+				switch mode {
+				case MaskAnd:
+					dst.Pix[dOff+x] = min(a, b)
+				case MaskOr:
+					dst.Pix[dOff+x] = max(a, b)
+				case MaskSubtract:
+					dst.Pix[dOff+x] = a - min(a, b)
+				}
+			}
+		}
+	}
+	return dst
+}
+
+// GenerateMaskWithExclusions generates a w x h annulus mask centered in the image, valid (255)
+// where the distance to the center lies within [inner, outer], then clears every exclusion rectangle.
+// Non-positive dimensions or a negative outer yield an empty mask; inner is clamped to [0, outer]
+func GenerateMaskWithExclusions(w, h int, inner, outer float64, exclusions []image.Rectangle) *image.Alpha {
+	mask := image.NewAlpha(image.Rect(0, 0, max(0, w), max(0, h)))
+	if w <= 0 || h <= 0 || outer < 0 {
+		return mask
+	}
+	inner = max(0, min(inner, outer))
+
+	cx, cy := float64(w-1)/2, float64(h-1)/2
+	innerSq, outerSq := inner*inner, outer*outer
+	for y := range h {
+		dy := float64(y) - cy
+		off := y * mask.Stride
+		for x := range w {
+			dx := float64(x) - cx
+			if d := dx*dx + dy*dy; d >= innerSq && d <= outerSq {
+				mask.Pix[off+x] = 255
+			}
+		}
+	}
+
+	for _, r := range exclusions {
+		r = r.Intersect(mask.Rect)
+		for y := r.Min.Y; y < r.Max.Y; y++ {
+			off := y * mask.Stride
+			clear(mask.Pix[off+r.Min.X : off+r.Max.X])
+		}
+	}
+	return mask
+}
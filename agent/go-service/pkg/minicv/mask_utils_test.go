@@ -0,0 +1,155 @@
+package minicv
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+func TestBuildStaticMaskFromReference(t *testing.T) {
+	empty := image.NewRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(empty, empty.Rect, &image.Uniform{color.RGBA{40, 40, 40, 255}}, image.Point{}, draw.Src)
+
+	withContent := image.NewRGBA(empty.Rect)
+	copy(withContent.Pix, empty.Pix)
+	content := image.Rect(5, 6, 12, 15)
+	draw.Draw(withContent, content, &image.Uniform{color.RGBA{200, 120, 40, 255}}, image.Point{}, draw.Src)
+	// A small change below the threshold must stay invalid
+	withContent.SetRGBA(0, 0, color.RGBA{45, 40, 40, 255})
+
+	mask, err := BuildStaticMaskFromReference(withContent, empty, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for y := range 20 {
+		for x := range 20 {
+			want := uint8(0)
+			if image.Pt(x, y).In(content) {
+				want = 255
+			}
+			if got := mask.AlphaAt(x, y).A; got != want {
+				t.Fatalf("mask at (%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestBuildStaticMaskFromReferenceSizeMismatch(t *testing.T) {
+	a := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	b := image.NewRGBA(image.Rect(0, 0, 10, 11))
+	if _, err := BuildStaticMaskFromReference(a, b, 0); err == nil {
+		t.Error("expected an error for mismatched sizes")
+	}
+}
+
+// circleMask returns a w x h mask that is valid inside the circle at (cx, cy) with radius r
+func circleMask(w, h, cx, cy, r int) *image.Alpha {
+	m := image.NewAlpha(image.Rect(0, 0, w, h))
+	for y := range h {
+		for x := range w {
+			if dx, dy := x-cx, y-cy; dx*dx+dy*dy <= r*r {
+				m.SetAlpha(x, y, color.Alpha{255})
+			}
+		}
+	}
+	return m
+}
+
+func TestCombineMasks(t *testing.T) {
+	a := circleMask(40, 30, 15, 15, 10)
+	b := circleMask(40, 30, 25, 15, 10)
+
+	and := CombineMasks(MaskAnd, a, b)
+	or := CombineMasks(MaskOr, a, b)
+	sub := CombineMasks(MaskSubtract, a, b)
+	if and == nil || or == nil || sub == nil {
+		t.Fatal("unexpected nil result")
+	}
+
+	intersection := 0
+	for y := range 30 {
+		for x := range 40 {
+			inA, inB := a.AlphaAt(x, y).A == 255, b.AlphaAt(x, y).A == 255
+			check := func(name string, m *image.Alpha, want bool) {
+				if got := m.AlphaAt(x, y).A == 255; got != want {
+					t.Fatalf("%s at (%d, %d) = %v, want %v", name, x, y, got, want)
+				}
+			}
+			check("AND", and, inA && inB)
+			check("OR", or, inA || inB)
+			check("SUBTRACT", sub, inA && !inB)
+			if inA && inB {
+				intersection++
+			}
+		}
+	}
+	if intersection == 0 {
+		t.Fatal("fixture circles should overlap")
+	}
+}
+
+func TestCombineMasksInvalid(t *testing.T) {
+	a := image.NewAlpha(image.Rect(0, 0, 10, 10))
+	if CombineMasks(MaskAnd) != nil {
+		t.Error("no masks should return nil")
+	}
+	if CombineMasks(MaskAnd, a, nil) != nil || CombineMasks(MaskOr, nil, a) != nil {
+		t.Error("nil mask should return nil")
+	}
+	if CombineMasks(MaskOr, a, image.NewAlpha(image.Rect(0, 0, 10, 11))) != nil {
+		t.Error("size mismatch should return nil")
+	}
+	if CombineMasks(MaskOp(99), a, a) != nil || CombineMasks(MaskOp(-1), a) != nil {
+		t.Error("unknown operation should return nil")
+	}
+}
+
+func countValid(m *image.Alpha) int {
+	n := 0
+	for _, a := range m.Pix {
+		if a == 255 {
+			n++
+		}
+	}
+	return n
+}
+
+func TestGenerateMaskWithExclusions(t *testing.T) {
+	// Knock out a strip along the bottom, like a scoreboard overlay
+	strip := image.Rect(0, 34, 41, 41)
+	base := GenerateMaskWithExclusions(41, 41, 5, 20, nil)
+	mask := GenerateMaskWithExclusions(41, 41, 5, 20, []image.Rectangle{strip, image.Rect(-10, -10, -1, -1)})
+
+	for y := range 41 {
+		for x := range 41 {
+			dx, dy := float64(x-20), float64(y-20)
+			d := dx*dx + dy*dy
+			want := d >= 25 && d <= 400 && !image.Pt(x, y).In(strip)
+			if got := mask.AlphaAt(x, y).A == 255; got != want {
+				t.Fatalf("mask at (%d, %d) = %v, want %v", x, y, got, want)
+			}
+		}
+	}
+	if countValid(mask) >= countValid(base) {
+		t.Error("exclusion should remove valid pixels")
+	}
+}
+
+func TestGenerateMaskWithExclusionsDegenerate(t *testing.T) {
+	if m := GenerateMaskWithExclusions(11, 11, 0, -3, nil); countValid(m) != 0 {
+		t.Errorf("negative outer should yield an empty mask, got %d valid pixels", countValid(m))
+	}
+	if m := GenerateMaskWithExclusions(0, 5, 0, 3, nil); !m.Rect.Empty() {
+		t.Errorf("zero width should yield an empty mask, got %v", m.Rect)
+	}
+	if m := GenerateMaskWithExclusions(-4, 5, 0, 3, nil); !m.Rect.Empty() {
+		t.Errorf("negative width should yield an empty mask, got %v", m.Rect)
+	}
+
+	// inner > outer clamps inner to outer, leaving only the ring at distance outer
+	ring := GenerateMaskWithExclusions(11, 11, 8, 3, nil)
+	if n := countValid(ring); n != countValid(GenerateMaskWithExclusions(11, 11, 3, 3, nil)) || n == 0 {
+		t.Errorf("inner > outer should behave like inner == outer, got %d valid pixels", n)
+	}
+}
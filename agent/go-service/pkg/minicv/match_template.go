@@ -2,6 +2,7 @@ package minicv
 
 import (
 	"image"
+	"image/draw"
 )
 
 // ComputeNCC computes the normalized cross-correlation between a rectangle region in the haystack image
@@ -116,3 +117,37 @@ func MatchTemplateInArea(
 	}
 	return fx, fy, fm
 }
+
+// LandmarkThreshold is the minimum NCC score for VerifyLandmark to accept a landmark
+const LandmarkThreshold = 0.8
+
+// VerifyLandmark checks that a small landmark template appears near its expected top-left position.
+// Only the neighbourhood expanded by tolerance pixels is searched, so it is cheap enough
+// to confirm a coarse match. Returns whether the best score reaches LandmarkThreshold, and that score
+func VerifyLandmark(img *image.RGBA, landmark *image.RGBA, expectedAt image.Point, tolerance int) (bool, float64) {
+	lw, lh := landmark.Rect.Dx(), landmark.Rect.Dy()
+	area := image.Rect(
+		expectedAt.X-tolerance, expectedAt.Y-tolerance,
+		expectedAt.X+lw+tolerance, expectedAt.Y+lh+tolerance,
+	).Intersect(img.Rect)
+	if area.Dx() < lw || area.Dy() < lh {
+		return false, 0.0
+	}
+
+	patch := image.NewRGBA(image.Rect(0, 0, area.Dx(), area.Dy()))
+	draw.Draw(patch, patch.Rect, img, area.Min, draw.Src)
+
+	landmarkStats := GetImageStats(landmark)
+	if landmarkStats.Std < 1e-6 {
+		return false, 0.0
+	}
+	// The neighbourhood is tiny, so search it exhaustively instead of on a coarse grid
+	integral := GetIntegralArray(patch)
+	score := -1.0
+	for y := 0; y <= area.Dy()-lh; y++ {
+		for x := 0; x <= area.Dx()-lw; x++ {
+			score = max(score, ComputeNCC(patch, integral, landmark, landmarkStats, x, y))
+		}
+	}
+	return score >= LandmarkThreshold, score
+}
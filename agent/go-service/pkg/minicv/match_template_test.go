@@ -0,0 +1,36 @@
+package minicv
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math/rand"
+	"testing"
+)
+
+func TestVerifyLandmark(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	img := image.NewRGBA(image.Rect(0, 0, 120, 120))
+	for i := range img.Pix {
+		img.Pix[i] = uint8(r.Intn(256))
+	}
+	landmark := image.NewRGBA(image.Rect(0, 0, 12, 12))
+	draw.Draw(landmark, landmark.Rect, img, image.Pt(50, 60), draw.Src)
+
+	// The landmark is 2px right and 3px above the expected position, within tolerance
+	ok, score := VerifyLandmark(img, landmark, image.Pt(48, 63), 5)
+	if !ok || score < 0.99 {
+		t.Errorf("landmark at correct offset: ok = %v, score = %.3f", ok, score)
+	}
+
+	// Out of tolerance
+	if ok, _ := VerifyLandmark(img, landmark, image.Pt(30, 80), 5); ok {
+		t.Error("landmark outside tolerance should not verify")
+	}
+
+	// Landmark erased from the image
+	draw.Draw(img, image.Rect(40, 50, 70, 80), &image.Uniform{color.RGBA{90, 90, 90, 255}}, image.Point{}, draw.Src)
+	if ok, score := VerifyLandmark(img, landmark, image.Pt(48, 63), 5); ok {
+		t.Errorf("absent landmark should not verify, score = %.3f", score)
+	}
+}
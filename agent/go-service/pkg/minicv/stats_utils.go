@@ -22,6 +22,9 @@ type IntegralArray struct {
 // GetImageStats computes the mean and standard deviation of pixel values in an image
 func GetImageStats(img *image.RGBA) StatsResult {
 	w, h := img.Rect.Dx(), img.Rect.Dy()
+	if w <= 0 || h <= 0 {
+		return StatsResult{}
+	}
 	ipx, is := img.Pix, img.Stride
 
 	sum := 0.0
@@ -87,6 +90,9 @@ func (ia *IntegralArray) GetAreaIntegral(x, y, w, h int) (float64, float64) {
 
 // GetAreaStats returns the mean and standard deviation (unnormalized) for a given rectangle area using the integral array
 func (ia *IntegralArray) GetAreaStats(x, y, w, h int) StatsResult {
+	if w <= 0 || h <= 0 {
+		return StatsResult{}
+	}
 	sum, sumSq := ia.GetAreaIntegral(x, y, w, h)
 	count := float64(w * h * 3)
 	mean := sum / count
@@ -96,3 +102,67 @@ func (ia *IntegralArray) GetAreaStats(x, y, w, h int) StatsResult {
 	}
 	return StatsResult{mean, math.Sqrt(variance)}
 }
+
+// WeightedMatch is a match position with a confidence weight, used for fusing results of different matchers
+type WeightedMatch struct {
+	X, Y   int
+	Weight float64
+}
+
+// FuseMatches computes the confidence-weighted centroid of the given match positions.
+// The spread is the weighted RMS distance to the centroid, which grows when the matchers disagree.
+// Matches with non-positive or non-finite weight are ignored; if none remain, (0, 0, 0) is returned
+func FuseMatches(results []WeightedMatch) (x, y, spread float64) {
+	totalWeight := 0.0
+	for _, r := range results {
+		if r.Weight > 0 && !math.IsInf(r.Weight, 1) {
+			totalWeight += r.Weight
+			x += float64(r.X) * r.Weight
+			y += float64(r.Y) * r.Weight
+		}
+	}
+	if totalWeight <= 0 {
+		return 0, 0, 0
+	}
+	x /= totalWeight
+	y /= totalWeight
+
+	variance := 0.0
+	for _, r := range results {
+		if r.Weight > 0 && !math.IsInf(r.Weight, 1) {
+			dx, dy := float64(r.X)-x, float64(r.Y)-y
+			variance += (dx*dx + dy*dy) * r.Weight
+		}
+	}
+	return x, y, math.Sqrt(variance / totalWeight)
+}
+
+// ColorDistance returns a perceptually weighted distance between two RGB colors
+// using the "redmean" approximation, which weights channels by the mean red level
+func ColorDistance(r1, g1, b1, r2, g2, b2 int) int {
+	rMean := (r1 + r2) / 2
+	dr, dg, db := r1-r2, g1-g2, b1-b2
+	d := (((512 + rMean) * dr * dr) >> 8) + 4*dg*dg + (((767 - rMean) * db * db) >> 8)
+	return int(math.Sqrt(float64(d)))
+}
+
+// DiffToConfidence maps a lower-is-better average difference into a [0, 1] confidence
+// via 1/(1+avgDiff/scale), where scale is the difference that yields a confidence of 0.5.
+// A NaN difference or a scale that is not positive (including NaN) yields 0
+func DiffToConfidence(avgDiff float64, scale float64) float64 {
+	if math.IsNaN(avgDiff) || !(scale > 0) {
+		return 0
+	}
+	if avgDiff <= 0 {
+		return 1
+	}
+	return 1 / (1 + avgDiff/scale)
+}
+
+// NCCToConfidence maps a normalized cross-correlation score in [-1, 1] linearly into a [0, 1] confidence
+func NCCToConfidence(ncc float64) float64 {
+	if math.IsNaN(ncc) {
+		return 0
+	}
+	return max(0, min(1, (ncc+1)/2))
+}
@@ -0,0 +1,140 @@
+package minicv
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func TestFuseMatches(t *testing.T) {
+	// Agreeing results fuse to their weighted centroid with a small spread
+	x, y, spread := FuseMatches([]WeightedMatch{{100, 200, 3}, {102, 200, 1}})
+	if math.Abs(x-100.5) > 1e-9 || math.Abs(y-200) > 1e-9 {
+		t.Errorf("centroid = (%.2f, %.2f), want (100.5, 200)", x, y)
+	}
+	if spread > 1.5 {
+		t.Errorf("agreeing spread = %.2f, want small", spread)
+	}
+
+	// Disagreeing results produce a large spread
+	_, _, spread = FuseMatches([]WeightedMatch{{0, 0, 1}, {300, 400, 1}})
+	if math.Abs(spread-250) > 1e-9 {
+		t.Errorf("disagreeing spread = %.2f, want 250", spread)
+	}
+
+	// Non-positive weights are ignored, and no usable weight yields zeros
+	x, y, _ = FuseMatches([]WeightedMatch{{10, 10, 1}, {500, 500, 0}, {900, 900, -2}})
+	if x != 10 || y != 10 {
+		t.Errorf("centroid = (%.2f, %.2f), want (10, 10)", x, y)
+	}
+	if x, y, spread := FuseMatches(nil); x != 0 || y != 0 || spread != 0 {
+		t.Errorf("empty input = (%v, %v, %v), want zeros", x, y, spread)
+	}
+}
+
+func TestColorDistancePerceptualRanking(t *testing.T) {
+	// On a red base, a blue shift is perceptually smaller than a green shift,
+	// even though plain SAD ranks the green shift (30) as nearer than the blue one (33)
+	r, g, b := 255, 100, 100
+	greenShift := ColorDistance(r, g, b, r, g+30, b)
+	blueShift := ColorDistance(r, g, b, r, g, b+33)
+	if blueShift >= greenShift {
+		t.Errorf("blue shift distance %d should be below green shift distance %d", blueShift, greenShift)
+	}
+
+	if d := ColorDistance(12, 34, 56, 12, 34, 56); d != 0 {
+		t.Errorf("identical colors distance = %d, want 0", d)
+	}
+	if ColorDistance(10, 200, 30, 90, 20, 250) != ColorDistance(90, 20, 250, 10, 200, 30) {
+		t.Error("distance should be symmetric")
+	}
+}
+
+func assertFinite(t *testing.T, name string, values ...float64) {
+	t.Helper()
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			t.Errorf("%s returned non-finite value %v", name, v)
+		}
+	}
+}
+
+func TestStatsDegenerateInputs(t *testing.T) {
+	empty := image.NewRGBA(image.Rect(0, 0, 0, 0))
+	s := GetImageStats(empty)
+	assertFinite(t, "GetImageStats(empty)", s.Mean, s.Std)
+
+	flat := image.NewRGBA(image.Rect(0, 0, 8, 8))
+	s = GetImageStats(flat)
+	assertFinite(t, "GetImageStats(flat)", s.Mean, s.Std)
+	if s.Std != 0 {
+		t.Errorf("flat image std = %v, want 0", s.Std)
+	}
+
+	integral := GetIntegralArray(flat)
+	s = integral.GetAreaStats(2, 2, 0, 3)
+	assertFinite(t, "GetAreaStats(zero width)", s.Mean, s.Std)
+	s = integral.GetAreaStats(2, 2, 3, 0)
+	assertFinite(t, "GetAreaStats(zero height)", s.Mean, s.Std)
+
+	ncc := ComputeNCC(flat, integral, empty, GetImageStats(empty), 0, 0)
+	assertFinite(t, "ComputeNCC(empty template)", ncc)
+	ncc = ComputeNCC(flat, integral, flat, GetImageStats(flat), 0, 0)
+	assertFinite(t, "ComputeNCC(flat template)", ncc)
+
+	x, y, spread := FuseMatches([]WeightedMatch{{1, 2, math.Inf(1)}, {3, 4, 1}, {5, 6, math.NaN()}})
+	assertFinite(t, "FuseMatches(non-finite weights)", x, y, spread)
+	if x != 3 || y != 4 {
+		t.Errorf("FuseMatches should ignore non-finite weights, got (%v, %v)", x, y)
+	}
+}
+
+func TestDiffToConfidence(t *testing.T) {
+	if c := DiffToConfidence(0, 10); c != 1 {
+		t.Errorf("zero diff confidence = %v, want 1", c)
+	}
+	if c := DiffToConfidence(10, 10); math.Abs(c-0.5) > 1e-12 {
+		t.Errorf("diff equal to scale confidence = %v, want 0.5", c)
+	}
+	if c := DiffToConfidence(1e12, 10); c <= 0 || c > 1e-9 {
+		t.Errorf("huge diff confidence = %v, want just above 0", c)
+	}
+	if c := DiffToConfidence(5, 0); c != 0 {
+		t.Errorf("non-positive scale confidence = %v, want 0", c)
+	}
+	if c := DiffToConfidence(5, math.NaN()); c != 0 {
+		t.Errorf("NaN scale confidence = %v, want 0", c)
+	}
+	if c := DiffToConfidence(math.NaN(), 10); c != 0 {
+		t.Errorf("NaN diff confidence = %v, want 0", c)
+	}
+
+	prev := 2.0
+	for d := 0.0; d <= 200; d += 0.5 {
+		c := DiffToConfidence(d, 15)
+		if c < 0 || c > 1 || c >= prev {
+			t.Fatalf("confidence not strictly decreasing in [0, 1] at diff %v: %v (prev %v)", d, c, prev)
+		}
+		prev = c
+	}
+}
+
+func TestNCCToConfidence(t *testing.T) {
+	cases := []struct{ ncc, want float64 }{
+		{-1, 0}, {0, 0.5}, {1, 1}, {-3, 0}, {2, 1}, {math.NaN(), 0},
+	}
+	for _, c := range cases {
+		if got := NCCToConfidence(c.ncc); got != c.want {
+			t.Errorf("NCCToConfidence(%v) = %v, want %v", c.ncc, got, c.want)
+		}
+	}
+
+	prev := -1.0
+	for ncc := -1.0; ncc <= 1; ncc += 0.01 {
+		c := NCCToConfidence(ncc)
+		if c <= prev {
+			t.Fatalf("confidence not strictly increasing at ncc %v", ncc)
+		}
+		prev = c
+	}
+}